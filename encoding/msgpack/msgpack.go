@@ -1,39 +1,74 @@
 package msgpack
 
+import (
+	"io"
+)
+
 import (
 	mp "github.com/ugorji/go/codec"
 )
 
 import (
-	"github.com/xd-luqiang/grpc-go/encoding"
-	"github.com/xd-luqiang/grpc-go/encoding/raw_proto"
+	"github.com/dubbogo/grpc-go/encoding"
+	"github.com/dubbogo/grpc-go/encoding/raw_proto"
 )
 
 func init() {
 	encoding.RegisterCodec(encoding.NewPBWrapperTwoWayCodec("msgpack", NewMsgPackCodec(), raw_proto.NewProtobufCodec()))
+	encoding.RegisterCodecFactory("raw_msgpack", func(opts ...encoding.CodecOption) encoding.Codec {
+		c := &MsgPackCodec{handle: new(mp.MsgpackHandle)}
+		for _, opt := range opts {
+			encoding.ApplyCodecOption(opt, c)
+		}
+		return c
+	})
 }
 
-// MsgPackCodec is the msgpack impl of common.Codec interface
-type MsgPackCodec struct{}
+// MsgPackCodec is the msgpack impl of encoding.Codec interface. It also
+// implements encoding.StreamingCodec, so large payloads can be (un)marshaled
+// directly against a transport io.Writer/io.Reader instead of being buffered
+// into a []byte first.
+type MsgPackCodec struct {
+	handle *mp.MsgpackHandle
+}
 
 func (p *MsgPackCodec) Name() string {
 	return "raw_msgpack"
 }
 
+func (p *MsgPackCodec) msgpackHandle() *mp.MsgpackHandle {
+	if p.handle == nil {
+		return new(mp.MsgpackHandle)
+	}
+	return p.handle
+}
+
 // Marshal serialize interface @v to bytes
 func (p *MsgPackCodec) Marshal(v interface{}) ([]byte, error) {
 	var out []byte
-	encoder := mp.NewEncoderBytes(&out, new(mp.MsgpackHandle))
+	encoder := mp.NewEncoderBytes(&out, p.msgpackHandle())
 	return out, encoder.Encode(v)
 }
 
 // Unmarshal deserialize @data to interface
 func (p *MsgPackCodec) Unmarshal(data []byte, v interface{}) error {
-	dec := mp.NewDecoderBytes(data, new(mp.MsgpackHandle))
+	dec := mp.NewDecoderBytes(data, p.msgpackHandle())
 	return dec.Decode(v)
 }
 
+// NewEncoder returns an encoding.Encoder that streams msgpack-encoded
+// messages directly to w, without an intermediate []byte copy.
+func (p *MsgPackCodec) NewEncoder(w io.Writer) encoding.Encoder {
+	return mp.NewEncoder(w, p.msgpackHandle())
+}
+
+// NewDecoder returns an encoding.Decoder that streams msgpack-encoded
+// messages directly from r, without an intermediate []byte copy.
+func (p *MsgPackCodec) NewDecoder(r io.Reader) encoding.Decoder {
+	return mp.NewDecoder(r, p.msgpackHandle())
+}
+
 // NewMsgPackCodec returns new ProtobufCodec
 func NewMsgPackCodec() encoding.Codec {
-	return &MsgPackCodec{}
+	return &MsgPackCodec{handle: new(mp.MsgpackHandle)}
 }
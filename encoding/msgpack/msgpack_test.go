@@ -0,0 +1,76 @@
+package msgpack
+
+import (
+	"bytes"
+	"testing"
+)
+
+import (
+	"github.com/dubbogo/grpc-go/encoding"
+)
+
+type msgpackPayload struct {
+	Name  string
+	Count int
+}
+
+func TestMsgPackCodecMarshalUnmarshalRoundTrip(t *testing.T) {
+	c := NewMsgPackCodec()
+	in := &msgpackPayload{Name: "widget", Count: 3}
+
+	data, err := c.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var out msgpackPayload
+	if err := c.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out != *in {
+		t.Errorf("Unmarshal() = %+v, want %+v", out, *in)
+	}
+}
+
+func TestMsgPackCodecStreamingRoundTrip(t *testing.T) {
+	sc, ok := NewMsgPackCodec().(encoding.StreamingCodec)
+	if !ok {
+		t.Fatalf("MsgPackCodec does not implement encoding.StreamingCodec")
+	}
+
+	var buf bytes.Buffer
+	in := &msgpackPayload{Name: "gadget", Count: 7}
+	if err := sc.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var out msgpackPayload
+	if err := sc.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if out != *in {
+		t.Errorf("Decode() = %+v, want %+v", out, *in)
+	}
+}
+
+func TestRegisterCodecFactoryAppliesOptionToConcreteCodec(t *testing.T) {
+	factory := encoding.GetCodecFactory("raw_msgpack")
+	if factory == nil {
+		t.Fatalf("GetCodecFactory(%q) = nil, want the factory registered by this package's init", "raw_msgpack")
+	}
+
+	var sawCodec bool
+	opt := encoding.NewCodecOption(func(v interface{}) {
+		if _, ok := v.(*MsgPackCodec); ok {
+			sawCodec = true
+		}
+	})
+
+	c := factory(opt)
+	if c.Name() != "raw_msgpack" {
+		t.Errorf("factory(opt).Name() = %q, want %q", c.Name(), "raw_msgpack")
+	}
+	if !sawCodec {
+		t.Errorf("option never saw a *MsgPackCodec; factory is not passing the concrete codec to CodecOption.apply")
+	}
+}
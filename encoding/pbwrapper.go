@@ -0,0 +1,52 @@
+/*
+ *
+ * Copyright 2021 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package encoding
+
+// pbWrapperTwoWayCodec is named name and uses primary for Marshal, but accepts
+// messages produced by either primary or fallback on Unmarshal, so that a
+// codec (e.g. msgpack) can be rolled out under a shared content-subtype
+// without breaking peers that are still sending plain protobuf wire bytes.
+type pbWrapperTwoWayCodec struct {
+	name     string
+	primary  Codec
+	fallback Codec
+}
+
+func (c *pbWrapperTwoWayCodec) Name() string {
+	return c.name
+}
+
+func (c *pbWrapperTwoWayCodec) Marshal(v interface{}) ([]byte, error) {
+	return c.primary.Marshal(v)
+}
+
+func (c *pbWrapperTwoWayCodec) Unmarshal(data []byte, v interface{}) error {
+	if err := c.primary.Unmarshal(data, v); err == nil {
+		return nil
+	}
+	return c.fallback.Unmarshal(data, v)
+}
+
+// NewPBWrapperTwoWayCodec returns a Codec whose Name is name that marshals
+// with primary and, on Unmarshal, falls back to fallback (typically plain
+// protobuf) if primary fails to decode the wire bytes. It does not register
+// the Codec; pass it to RegisterCodec for that.
+func NewPBWrapperTwoWayCodec(name string, primary, fallback Codec) Codec {
+	return &pbWrapperTwoWayCodec{name: name, primary: primary, fallback: fallback}
+}
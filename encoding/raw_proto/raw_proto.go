@@ -0,0 +1,63 @@
+/*
+ *
+ * Copyright 2021 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package raw_proto is the plain protobuf Codec used as the wire-compatible
+// fallback for wrapper codecs such as msgpack's two-way codec.
+package raw_proto
+
+import (
+	"fmt"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+import (
+	"github.com/dubbogo/grpc-go/encoding"
+)
+
+// ProtobufCodec is the plain protobuf impl of encoding.Codec.
+type ProtobufCodec struct{}
+
+func (c *ProtobufCodec) Name() string {
+	return "proto"
+}
+
+// Marshal serializes v, which must be a proto.Message, to its wire format.
+func (c *ProtobufCodec) Marshal(v interface{}) ([]byte, error) {
+	vv, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("raw_proto: failed to marshal, message is %T, want proto.Message", v)
+	}
+	return proto.Marshal(vv)
+}
+
+// Unmarshal parses the wire format into v, which must be a proto.Message.
+func (c *ProtobufCodec) Unmarshal(data []byte, v interface{}) error {
+	vv, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("raw_proto: failed to unmarshal, message is %T, want proto.Message", v)
+	}
+	return proto.Unmarshal(data, vv)
+}
+
+// NewProtobufCodec returns a new ProtobufCodec.
+func NewProtobufCodec() encoding.Codec {
+	return &ProtobufCodec{}
+}
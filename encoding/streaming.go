@@ -0,0 +1,126 @@
+/*
+ *
+ * Copyright 2022 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package encoding
+
+import (
+	"io"
+)
+
+// Encoder writes successive messages to an underlying io.Writer without
+// buffering the whole message in memory first.
+type Encoder interface {
+	// Encode writes v to the stream. It may be called multiple times on the
+	// same Encoder to write multiple messages.
+	Encode(v interface{}) error
+}
+
+// Decoder reads successive messages from an underlying io.Reader without
+// buffering the whole message in memory first.
+type Decoder interface {
+	// Decode reads the next message from the stream into v. It may be
+	// called multiple times on the same Decoder to read multiple messages.
+	Decode(v interface{}) error
+}
+
+// StreamingCodec is implemented by codecs that can (un)marshal directly
+// against an io.Writer/io.Reader instead of a []byte, so that large
+// messages don't need a full in-memory copy. SupportsStreaming reports
+// whether a Codec qualifies for streaming mode; the transport does not call
+// it, so RPCs are still (un)marshaled with Codec's byte-slice-based
+// Marshal/Unmarshal.
+type StreamingCodec interface {
+	Codec
+
+	// NewEncoder returns an Encoder that writes messages to w.
+	NewEncoder(w io.Writer) Encoder
+	// NewDecoder returns a Decoder that reads messages from r.
+	NewDecoder(r io.Reader) Decoder
+}
+
+// StreamingHeader is the metadata key for a peer to advertise that it
+// supports reading/writing the request's content-subtype via its
+// StreamingCodec rather than Codec's byte-slice Marshal/Unmarshal. The
+// transport neither sets this header on outgoing requests nor reads it from
+// incoming responses; see SupportsStreaming.
+const StreamingHeader = "grpc-encoding-stream"
+
+// SupportsStreaming reports whether codec should be used in streaming mode
+// for an RPC whose peer advertised streaming support (i.e. sent "true" for
+// StreamingHeader), as given by peerAdvertisedStreaming. It returns true
+// only if peerAdvertisedStreaming is true and codec implements
+// StreamingCodec.
+func SupportsStreaming(codec Codec, peerAdvertisedStreaming bool) bool {
+	if !peerAdvertisedStreaming {
+		return false
+	}
+	_, ok := codec.(StreamingCodec)
+	return ok
+}
+
+// CodecOption configures a Codec produced by a CodecFactory.
+type CodecOption interface {
+	apply(interface{})
+}
+
+type codecOptionFunc func(interface{})
+
+func (f codecOptionFunc) apply(c interface{}) {
+	f(c)
+}
+
+// NewCodecOption returns a CodecOption that applies fn to the Codec
+// instances built by a CodecFactory. fn should type-assert its argument to
+// the concrete Codec type it knows how to configure and silently no-op
+// otherwise, so the same option can be passed to unrelated factories.
+func NewCodecOption(fn func(interface{})) CodecOption {
+	return codecOptionFunc(fn)
+}
+
+// ApplyCodecOption applies opt to c. CodecFactory implementations living
+// outside this package call this instead of opt's unexported apply method
+// directly.
+func ApplyCodecOption(opt CodecOption, c interface{}) {
+	opt.apply(c)
+}
+
+// CodecFactory builds a Codec configured with the given options, e.g. a
+// msgpack extension registration or a hessian type map, without requiring a
+// new globally-registered codec name per configuration.
+type CodecFactory func(opts ...CodecOption) Codec
+
+var registeredCodecFactories = make(map[string]CodecFactory)
+
+// RegisterCodecFactory registers a factory under name so that per-call
+// codec instances can be built via GetCodecFactory. Like RegisterCodec, it
+// must be called from an init function and is not thread-safe afterwards.
+func RegisterCodecFactory(name string, factory CodecFactory) {
+	if factory == nil {
+		panic("cannot register a nil CodecFactory")
+	}
+	if name == "" {
+		panic("cannot register CodecFactory with empty string result for name")
+	}
+	registeredCodecFactories[name] = factory
+}
+
+// GetCodecFactory returns the CodecFactory registered for name, or nil if no
+// factory was registered for that name.
+func GetCodecFactory(name string) CodecFactory {
+	return registeredCodecFactories[name]
+}
@@ -21,6 +21,10 @@
 // This package is experimental.
 package metadata
 
+import (
+	"strings"
+)
+
 import (
 	"github.com/xd-luqiang/grpc-go/metadata"
 	"github.com/xd-luqiang/grpc-go/resolver"
@@ -30,18 +34,46 @@ type mdKeyType string
 
 const mdKey = mdKeyType("grpc.internal.address.metadata")
 
-type mdValue metadata.MD
+// scopedMD holds the metadata attached to an address, split by the scope it
+// applies to. def is applied to every RPC sent on a SubConn built from the
+// address; forService and forMethod only apply to RPCs against the matching
+// service ("pkg.Service") or full method ("/pkg.Service/Method").
+type scopedMD struct {
+	def        metadata.MD
+	forService map[string]metadata.MD
+	forMethod  map[string]metadata.MD
+}
 
-func (m mdValue) Equal(o interface{}) bool {
-	om, ok := o.(mdValue)
+func (m scopedMD) Equal(o interface{}) bool {
+	om, ok := o.(scopedMD)
 	if !ok {
 		return false
 	}
-	if len(m) != len(om) {
+	if !mdEqual(m.def, om.def) {
 		return false
 	}
-	for k, v := range m {
-		ov := om[k]
+	if len(m.forService) != len(om.forService) || len(m.forMethod) != len(om.forMethod) {
+		return false
+	}
+	for k, v := range m.forService {
+		if !mdEqual(v, om.forService[k]) {
+			return false
+		}
+	}
+	for k, v := range m.forMethod {
+		if !mdEqual(v, om.forMethod[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func mdEqual(a, b metadata.MD) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		ov := b[k]
 		if len(ov) != len(v) {
 			return false
 		}
@@ -54,21 +86,139 @@ func (m mdValue) Equal(o interface{}) bool {
 	return true
 }
 
-// Get returns the metadata of addr.
-func Get(addr resolver.Address) metadata.MD {
+// mergeMD returns a new metadata.MD holding every key/value pair from base
+// followed by every key/value pair from overlay, preserving the multi-value
+// semantics of metadata.MD: values for a key present in both are
+// concatenated rather than one replacing the other.
+func mergeMD(base, overlay metadata.MD) metadata.MD {
+	out := make(metadata.MD, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = append(out[k], v...)
+	}
+	for k, v := range overlay {
+		out[k] = append(out[k], v...)
+	}
+	return out
+}
+
+func get(addr resolver.Address) scopedMD {
 	attrs := addr.Attributes
 	if attrs == nil {
-		return nil
+		return scopedMD{}
 	}
-	md, _ := attrs.Value(mdKey).(mdValue)
-	return metadata.MD(md)
+	smd, _ := attrs.Value(mdKey).(scopedMD)
+	return smd
+}
+
+// Get returns the default (unscoped) metadata of addr, i.e. the metadata
+// that applies to every RPC sent on a SubConn built from addr.
+func Get(addr resolver.Address) metadata.MD {
+	return get(addr).def
 }
 
-// Set sets (overrides) the metadata in addr.
+// Set sets (overrides) the default metadata in addr.
 //
 // When a SubConn is created with this address, the RPCs sent on it will all
-// have this metadata.
+// have this metadata, merged with any metadata scoped more specifically via
+// SetForService or SetForMethods (see GetForMethod).
 func Set(addr resolver.Address, md metadata.MD) resolver.Address {
-	addr.Attributes = addr.Attributes.WithValue(mdKey, mdValue(md))
+	smd := get(addr)
+	smd.def = md
+	addr.Attributes = addr.Attributes.WithValue(mdKey, smd)
 	return addr
 }
+
+// Append merges md into the default metadata already attached to addr,
+// preserving the multi-value semantics of metadata.MD: values for keys
+// present in both are concatenated rather than one overwriting the other.
+func Append(addr resolver.Address, md metadata.MD) resolver.Address {
+	smd := get(addr)
+	smd.def = mergeMD(smd.def, md)
+	addr.Attributes = addr.Attributes.WithValue(mdKey, smd)
+	return addr
+}
+
+// Delete removes the given keys from the default metadata attached to addr.
+func Delete(addr resolver.Address, keys ...string) resolver.Address {
+	smd := get(addr)
+	if smd.def == nil {
+		return addr
+	}
+	del := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		del[strings.ToLower(k)] = true
+	}
+	def := make(metadata.MD, len(smd.def))
+	for k, v := range smd.def {
+		if del[strings.ToLower(k)] {
+			continue
+		}
+		def[k] = v
+	}
+	smd.def = def
+	addr.Attributes = addr.Attributes.WithValue(mdKey, smd)
+	return addr
+}
+
+// SetForMethods attaches md to addr so that it is only applied to RPCs whose
+// full method name (e.g. "/pkg.Service/Method") is one of methods.
+func SetForMethods(addr resolver.Address, md metadata.MD, methods ...string) resolver.Address {
+	smd := get(addr)
+	forMethod := make(map[string]metadata.MD, len(smd.forMethod)+len(methods))
+	for k, v := range smd.forMethod {
+		forMethod[k] = v
+	}
+	for _, method := range methods {
+		forMethod[method] = md
+	}
+	smd.forMethod = forMethod
+	addr.Attributes = addr.Attributes.WithValue(mdKey, smd)
+	return addr
+}
+
+// SetForService attaches md to addr so that it is only applied to RPCs
+// against service (e.g. "pkg.Service").
+func SetForService(addr resolver.Address, md metadata.MD, service string) resolver.Address {
+	smd := get(addr)
+	forService := make(map[string]metadata.MD, len(smd.forService)+1)
+	for k, v := range smd.forService {
+		forService[k] = v
+	}
+	forService[service] = md
+	smd.forService = forService
+	addr.Attributes = addr.Attributes.WithValue(mdKey, smd)
+	return addr
+}
+
+// GetForMethod returns the metadata attached to addr that applies to an RPC
+// against fullMethod (e.g. "/pkg.Service/Method"), combining the default,
+// service-scoped and method-scoped metadata in that order.
+//
+// Values for a key set at more than one scope are all kept, concatenated in
+// that order; no scope replaces another. GetForMethod only computes the
+// metadata: nothing in this package adds it to an RPC's outgoing context or
+// merges it with metadata already present there.
+func GetForMethod(addr resolver.Address, fullMethod string) metadata.MD {
+	smd := get(addr)
+	md := smd.def
+	if service, ok := serviceFromMethod(fullMethod); ok {
+		md = mergeMD(md, smd.forService[service])
+	}
+	md = mergeMD(md, smd.forMethod[fullMethod])
+	return md
+}
+
+// serviceFromMethod extracts the service portion ("pkg.Service") out of a
+// full method name ("/pkg.Service/Method").
+func serviceFromMethod(fullMethod string) (string, bool) {
+	method := fullMethod
+	if len(method) > 0 && method[0] == '/' {
+		method = method[1:]
+	}
+	for i := len(method) - 1; i >= 0; i-- {
+		if method[i] == '/' {
+			return method[:i], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,76 @@
+/*
+ *
+ * Copyright 2020 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package metadata
+
+import (
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/xd-luqiang/grpc-go/metadata"
+	"github.com/xd-luqiang/grpc-go/resolver"
+)
+
+func TestAppendMergesRatherThanOverwrites(t *testing.T) {
+	addr := Set(resolver.Address{}, metadata.MD{"k": []string{"v1"}})
+	addr = Append(addr, metadata.MD{"k": []string{"v2"}, "k2": []string{"v3"}})
+
+	want := metadata.MD{"k": []string{"v1", "v2"}, "k2": []string{"v3"}}
+	if got := Get(addr); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get(addr) = %v, want %v", got, want)
+	}
+}
+
+func TestDeleteRemovesKeyCaseInsensitively(t *testing.T) {
+	addr := Set(resolver.Address{}, metadata.MD{"K": []string{"v1"}, "other": []string{"v2"}})
+	addr = Delete(addr, "k")
+
+	want := metadata.MD{"other": []string{"v2"}}
+	if got := Get(addr); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get(addr) after Delete = %v, want %v", got, want)
+	}
+}
+
+func TestGetForMethodPrecedence(t *testing.T) {
+	addr := Set(resolver.Address{}, metadata.MD{"k": []string{"default"}})
+	addr = SetForService(addr, metadata.MD{"k": []string{"service"}}, "pkg.Service")
+	addr = SetForMethods(addr, metadata.MD{"k": []string{"method"}}, "/pkg.Service/Method")
+
+	got := GetForMethod(addr, "/pkg.Service/Method")
+	want := metadata.MD{"k": []string{"default", "service", "method"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetForMethod(addr, %q) = %v, want %v", "/pkg.Service/Method", got, want)
+	}
+
+	// A different method on the same service only picks up the
+	// service-scoped metadata, not the method-scoped metadata set above.
+	got = GetForMethod(addr, "/pkg.Service/Other")
+	want = metadata.MD{"k": []string{"default", "service"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetForMethod(addr, %q) = %v, want %v", "/pkg.Service/Other", got, want)
+	}
+
+	// An unrelated service only picks up the default metadata.
+	got = GetForMethod(addr, "/other.Service/Method")
+	want = metadata.MD{"k": []string{"default"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetForMethod(addr, %q) = %v, want %v", "/other.Service/Method", got, want)
+	}
+}
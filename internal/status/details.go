@@ -0,0 +1,219 @@
+/*
+ *
+ * Copyright 2020 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package status
+
+import (
+	"net/http"
+	"time"
+)
+
+import (
+	"github.com/golang/protobuf/ptypes"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+import (
+	"github.com/dubbogo/grpc-go/codes"
+)
+
+// WithRetryInfo returns a new status with a RetryInfo detail attached,
+// telling the client how long to wait before retrying the request.
+func (s *Status) WithRetryInfo(delay time.Duration) (*Status, error) {
+	return s.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(delay),
+	})
+}
+
+// WithQuotaFailure returns a new status with a QuotaFailure detail attached.
+func (s *Status) WithQuotaFailure(violations ...*errdetails.QuotaFailure_Violation) (*Status, error) {
+	return s.WithDetails(&errdetails.QuotaFailure{Violations: violations})
+}
+
+// WithBadRequest returns a new status with a BadRequest detail attached,
+// describing which request fields were invalid.
+func (s *Status) WithBadRequest(fieldViolations ...*errdetails.BadRequest_FieldViolation) (*Status, error) {
+	return s.WithDetails(&errdetails.BadRequest{FieldViolations: fieldViolations})
+}
+
+// WithPreconditionFailure returns a new status with a PreconditionFailure
+// detail attached.
+func (s *Status) WithPreconditionFailure(violations ...*errdetails.PreconditionFailure_Violation) (*Status, error) {
+	return s.WithDetails(&errdetails.PreconditionFailure{Violations: violations})
+}
+
+// WithErrorInfo returns a new status with an ErrorInfo detail attached,
+// identifying the cause of the error with a reason scoped to a domain.
+func (s *Status) WithErrorInfo(reason, domain string, meta map[string]string) (*Status, error) {
+	return s.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   domain,
+		Metadata: meta,
+	})
+}
+
+// WithResourceInfo returns a new status with a ResourceInfo detail attached,
+// describing the resource that caused the error.
+func (s *Status) WithResourceInfo(resourceType, resourceName, owner, description string) (*Status, error) {
+	return s.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Owner:        owner,
+		Description:  description,
+	})
+}
+
+// WithHelp returns a new status with a Help detail attached, pointing the
+// caller at documentation or remediation steps.
+func (s *Status) WithHelp(links ...*errdetails.Help_Link) (*Status, error) {
+	return s.WithDetails(&errdetails.Help{Links: links})
+}
+
+// WithLocalizedMessage returns a new status with a LocalizedMessage detail
+// attached, carrying a message suitable for display to an end user.
+func (s *Status) WithLocalizedMessage(locale, msg string) (*Status, error) {
+	return s.WithDetails(&errdetails.LocalizedMessage{
+		Locale:  locale,
+		Message: msg,
+	})
+}
+
+// RetryInfo returns the first RetryInfo detail attached to s, or nil if none
+// is present.
+func (s *Status) RetryInfo() *errdetails.RetryInfo {
+	for _, d := range s.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			return ri
+		}
+	}
+	return nil
+}
+
+// QuotaFailure returns the first QuotaFailure detail attached to s, or nil if
+// none is present.
+func (s *Status) QuotaFailure() *errdetails.QuotaFailure {
+	for _, d := range s.Details() {
+		if qf, ok := d.(*errdetails.QuotaFailure); ok {
+			return qf
+		}
+	}
+	return nil
+}
+
+// BadRequest returns the first BadRequest detail attached to s, or nil if
+// none is present.
+func (s *Status) BadRequest() *errdetails.BadRequest {
+	for _, d := range s.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			return br
+		}
+	}
+	return nil
+}
+
+// PreconditionFailure returns the first PreconditionFailure detail attached
+// to s, or nil if none is present.
+func (s *Status) PreconditionFailure() *errdetails.PreconditionFailure {
+	for _, d := range s.Details() {
+		if pf, ok := d.(*errdetails.PreconditionFailure); ok {
+			return pf
+		}
+	}
+	return nil
+}
+
+// ErrorInfo returns the first ErrorInfo detail attached to s, or nil if none
+// is present.
+func (s *Status) ErrorInfo() *errdetails.ErrorInfo {
+	for _, d := range s.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			return ei
+		}
+	}
+	return nil
+}
+
+// ResourceInfo returns the first ResourceInfo detail attached to s, or nil if
+// none is present.
+func (s *Status) ResourceInfo() *errdetails.ResourceInfo {
+	for _, d := range s.Details() {
+		if ri, ok := d.(*errdetails.ResourceInfo); ok {
+			return ri
+		}
+	}
+	return nil
+}
+
+// Help returns the first Help detail attached to s, or nil if none is
+// present.
+func (s *Status) Help() *errdetails.Help {
+	for _, d := range s.Details() {
+		if h, ok := d.(*errdetails.Help); ok {
+			return h
+		}
+	}
+	return nil
+}
+
+// LocalizedMessage returns the first LocalizedMessage detail attached to s,
+// or nil if none is present.
+func (s *Status) LocalizedMessage() *errdetails.LocalizedMessage {
+	for _, d := range s.Details() {
+		if lm, ok := d.(*errdetails.LocalizedMessage); ok {
+			return lm
+		}
+	}
+	return nil
+}
+
+// codeToHTTPStatus maps codes.Code to the HTTP status recommended by the
+// google.rpc error model (see
+// https://cloud.google.com/apis/design/errors#handling_errors).
+var codeToHTTPStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+// HTTPStatusCode maps s's code and attached details to the HTTP status code
+// recommended for it by the google.rpc error model. A QuotaFailure detail,
+// for example, always maps to 429 regardless of the underlying code.
+func (s *Status) HTTPStatusCode() int {
+	if s.QuotaFailure() != nil {
+		return http.StatusTooManyRequests
+	}
+	if hs, ok := codeToHTTPStatus[s.Code()]; ok {
+		return hs
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,80 @@
+/*
+ *
+ * Copyright 2020 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package status
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+import (
+	"github.com/dubbogo/grpc-go/codes"
+)
+
+func TestWithRetryInfoRoundTrip(t *testing.T) {
+	s, err := New(codes.Unavailable, "try again").WithRetryInfo(2 * time.Second)
+	if err != nil {
+		t.Fatalf("WithRetryInfo() error = %v", err)
+	}
+
+	ri := s.RetryInfo()
+	if ri == nil {
+		t.Fatalf("RetryInfo() = nil, want the attached detail")
+	}
+	if got := ri.RetryDelay.AsDuration(); got != 2*time.Second {
+		t.Errorf("RetryInfo().RetryDelay = %v, want %v", got, 2*time.Second)
+	}
+}
+
+func TestHTTPStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *Status
+		want int
+	}{
+		{"not found", New(codes.NotFound, "missing"), http.StatusNotFound},
+		{"invalid argument", New(codes.InvalidArgument, "bad"), http.StatusBadRequest},
+		{"unavailable", New(codes.Unavailable, "down"), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.HTTPStatusCode(); got != tt.want {
+				t.Errorf("HTTPStatusCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPStatusCodeQuotaFailureOverridesCode(t *testing.T) {
+	s, err := New(codes.Internal, "quota").WithQuotaFailure(&errdetails.QuotaFailure_Violation{
+		Subject:     "project:123",
+		Description: "too many requests",
+	})
+	if err != nil {
+		t.Fatalf("WithQuotaFailure() error = %v", err)
+	}
+	if got, want := s.HTTPStatusCode(), http.StatusTooManyRequests; got != want {
+		t.Errorf("HTTPStatusCode() with a QuotaFailure detail = %d, want %d", got, want)
+	}
+}
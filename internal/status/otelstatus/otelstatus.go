@@ -0,0 +1,94 @@
+/*
+ *
+ * Copyright 2022 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package otelstatus wires internal/status up to OpenTelemetry. It is kept
+// as a separate subpackage so that the core status package does not pull in
+// an OpenTelemetry dependency for callers who don't use tracing.
+package otelstatus
+
+import (
+	"context"
+	"fmt"
+)
+
+import (
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+import (
+	grpccodes "github.com/dubbogo/grpc-go/codes"
+	"github.com/dubbogo/grpc-go/internal/status"
+)
+
+// FromContext returns a Status representing c and msg. If ctx carries an
+// active span, the error is recorded on it and the span's trace context is
+// attached to the returned Status so a client that receives it can
+// correlate the failure back to the server span.
+func FromContext(ctx context.Context, c grpccodes.Code, msg string) *status.Status {
+	s := status.New(c, msg)
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return s
+	}
+	span.RecordError(s.Err(), trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, msg)
+	sc := span.SpanContext()
+	withTrace, err := s.WithDetails(&errdetails.RequestInfo{
+		RequestId:   sc.TraceID().String(),
+		ServingData: sc.SpanID().String(),
+	})
+	if err != nil {
+		return s
+	}
+	return withTrace
+}
+
+// Errorf returns FromContext(ctx, c, fmt.Sprintf(format, a...)).Err().
+func Errorf(ctx context.Context, c grpccodes.Code, format string, a ...interface{}) error {
+	return FromContext(ctx, c, fmt.Sprintf(format, a...)).Err()
+}
+
+// SpanContext extracts the W3C trace context that FromContext attached to s,
+// if any.
+func SpanContext(s *status.Status) (trace.SpanContext, bool) {
+	if s == nil {
+		return trace.SpanContext{}, false
+	}
+	for _, d := range s.Details() {
+		ri, ok := d.(*errdetails.RequestInfo)
+		if !ok {
+			continue
+		}
+		traceID, err := trace.TraceIDFromHex(ri.RequestId)
+		if err != nil {
+			continue
+		}
+		spanID, err := trace.SpanIDFromHex(ri.ServingData)
+		if err != nil {
+			continue
+		}
+		return trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}), true
+	}
+	return trace.SpanContext{}, false
+}
@@ -0,0 +1,52 @@
+package otelstatus
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"go.opentelemetry.io/otel/trace"
+)
+
+import (
+	grpccodes "github.com/dubbogo/grpc-go/codes"
+)
+
+func TestFromContextWithoutSpanReturnsPlainStatus(t *testing.T) {
+	s := FromContext(context.Background(), grpccodes.Internal, "boom")
+
+	if s.Code() != grpccodes.Internal || s.Message() != "boom" {
+		t.Fatalf("FromContext() = {%v, %q}, want {%v, %q}", s.Code(), s.Message(), grpccodes.Internal, "boom")
+	}
+	if _, ok := SpanContext(s); ok {
+		t.Errorf("SpanContext(s) returned ok=true for a Status built without an active span")
+	}
+}
+
+func TestFromContextAttachesSpanContext(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	if err != nil {
+		t.Fatalf("TraceIDFromHex() error = %v", err)
+	}
+	spanID, err := trace.SpanIDFromHex("0102030405060708")
+	if err != nil {
+		t.Fatalf("SpanIDFromHex() error = %v", err)
+	}
+	want := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), want)
+
+	s := FromContext(ctx, grpccodes.Unavailable, "backend down")
+
+	got, ok := SpanContext(s)
+	if !ok {
+		t.Fatalf("SpanContext(s) returned ok=false, want the span context attached by FromContext")
+	}
+	if got.TraceID() != want.TraceID() || got.SpanID() != want.SpanID() {
+		t.Errorf("SpanContext(s) = %v, want trace/span IDs %v/%v", got, want.TraceID(), want.SpanID())
+	}
+}
@@ -173,6 +173,11 @@ func (s *Status) String() string {
 type Error struct {
 	s     *Status
 	stack stack
+
+	// cause is the original error passed to Wrap, if any. It is not
+	// serialized; it is only available to the side of the RPC that
+	// constructed this Error.
+	cause error
 }
 
 func (e *Error) Error() string {
@@ -205,16 +210,6 @@ func (e *Error) Stacks() string {
 	return stackTracesStr
 }
 
-// Is implements future error.Is functionality.
-// A Error is equivalent if the code and message are identical.
-func (e *Error) Is(target error) bool {
-	tse, ok := target.(*Error)
-	if !ok {
-		return false
-	}
-	return proto.Equal(e.s.s, tse.s.s)
-}
-
 // stack represents a stack of program counters.
 type stack []uintptr
 
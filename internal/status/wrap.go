@@ -0,0 +1,161 @@
+/*
+ *
+ * Copyright 2022 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package status
+
+import (
+	"errors"
+	"fmt"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+import (
+	"github.com/dubbogo/grpc-go/codes"
+)
+
+// errorTypeDomain is the ErrorInfo domain used to mark the cause wire detail
+// attached by Wrap, as opposed to an ErrorInfo a user attached themselves.
+const errorTypeDomain = "grpc-go/status-wrapped-error"
+
+// errorTypeRegistry maps the reason recorded by Wrap back to a constructor
+// for a sentinel-comparable error, so that Cause() can reconstruct an error
+// on which errors.Is/errors.As can traverse across the RPC boundary.
+var errorTypeRegistry = make(map[string]func(msg string) error)
+
+// RegisterErrorType registers a constructor for errors of the given reason
+// (as recorded on the wire by Wrap) so that Status.Cause can reconstruct a
+// sentinel-comparable error for it on the receiving side. It is expected to
+// be called from an init function.
+func RegisterErrorType(reason string, newError func(msg string) error) {
+	errorTypeRegistry[reason] = newError
+}
+
+// Wrap returns an error with code c whose message is err.Error(). The
+// original err is preserved as the cause (reachable via errors.Unwrap on the
+// local side), and its Go type name is also serialized as an ErrorInfo
+// detail on the wire so that, if the corresponding reason was registered with
+// RegisterErrorType, the receiving side's Status.Cause can reconstruct a
+// comparable error from that type name and the status message. If c is OK,
+// Wrap returns nil.
+func Wrap(c codes.Code, err error) error {
+	if c == codes.OK {
+		return nil
+	}
+	s := New(c, err.Error())
+	reason := reasonForError(err)
+	withCause, wrapErr := s.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorTypeDomain,
+	})
+	if wrapErr != nil {
+		withCause = s
+	}
+	return &Error{s: withCause, cause: err}
+}
+
+// reasonForError returns the key under which err's constructor would have
+// been registered with RegisterErrorType: its dynamic Go type name.
+func reasonForError(err error) string {
+	return fmt.Sprintf("%T", err)
+}
+
+// FromError returns a Status representing err. If err implements the
+// GRPCStatus() *Status interface (as *Error does), that Status is returned
+// directly. Otherwise it returns a Status with code Unknown and message
+// err.Error(), carrying the ErrorInfo detail that Wrap attaches. err itself
+// is not kept on the Status, so Status.Cause reconstructs an error for it
+// only if its type was registered with RegisterErrorType.
+func FromError(err error) *Status {
+	if err == nil {
+		return nil
+	}
+	if se, ok := err.(interface{ GRPCStatus() *Status }); ok {
+		return se.GRPCStatus()
+	}
+	return Wrap(codes.Unknown, err).(*Error).s
+}
+
+// resolvedCause returns the cause to use for Unwrap/Is/As: e.cause if e was
+// built locally via Wrap, or failing that, the cause reconstructed from
+// e.s's wire-serialized ErrorInfo detail (e.g. for an Error built from a
+// Status that came off the wire via FromProto). It returns nil if neither is
+// available.
+func (e *Error) resolvedCause() error {
+	if e.cause != nil {
+		return e.cause
+	}
+	return e.s.Cause()
+}
+
+// Unwrap returns the cause captured when e was created via Wrap, or the
+// cause reconstructed from e's wire-serialized ErrorInfo detail if e was
+// instead built from a Status received over the wire. It returns nil if
+// neither is available.
+func (e *Error) Unwrap() error {
+	return e.resolvedCause()
+}
+
+// Cause reconstructs a sentinel-comparable error for the ErrorInfo detail
+// that Wrap attaches to s, using the constructor registered for its reason
+// via RegisterErrorType. It returns nil if s carries no such detail, or if
+// no constructor was registered for its reason.
+func (s *Status) Cause() error {
+	for _, d := range s.Details() {
+		ei, ok := d.(*errdetails.ErrorInfo)
+		if !ok || ei.Domain != errorTypeDomain {
+			continue
+		}
+		newError, ok := errorTypeRegistry[ei.Reason]
+		if !ok {
+			return nil
+		}
+		return newError(s.Message())
+	}
+	return nil
+}
+
+// Is implements future error.Is functionality.
+//
+// It first walks e's unwrap chain looking for a match, then falls back to
+// comparing the two Statuses by proto equality: two Errors compare equal
+// when their code, message and details (including the DebugInfo stack that
+// New attaches) are all equal.
+func (e *Error) Is(target error) bool {
+	if errors.Is(e.resolvedCause(), target) {
+		return true
+	}
+	tse, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return proto.Equal(e.s.s, tse.s.s)
+}
+
+// As implements future error.As functionality by delegating to e's unwrap
+// chain, so errors.As(rpcErr, &myAppErr) can recover a typed cause that a
+// server handler wrapped when returning the RPC error. After rpcErr has
+// been deserialized from a Status received over the wire, this works only
+// if the cause's type was registered with RegisterErrorType.
+func (e *Error) As(target interface{}) bool {
+	return errors.As(e.resolvedCause(), target)
+}
@@ -0,0 +1,69 @@
+/*
+ *
+ * Copyright 2022 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package status
+
+import (
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/dubbogo/grpc-go/codes"
+)
+
+type myAppError struct {
+	msg string
+}
+
+func (e *myAppError) Error() string {
+	return e.msg
+}
+
+func TestCauseSurvivesWireRoundTrip(t *testing.T) {
+	RegisterErrorType("*status.myAppError", func(msg string) error {
+		return &myAppError{msg: msg}
+	})
+
+	sent := Wrap(codes.Internal, &myAppError{msg: "backend unavailable"})
+
+	// Simulate the error crossing the RPC boundary: only the serialized
+	// Status proto survives, not the cause field set by Wrap.
+	received := FromProto(sent.(*Error).GRPCStatus().Proto()).Err()
+
+	var got *myAppError
+	if !errors.As(received, &got) {
+		t.Fatalf("errors.As(%v, &myAppError{}) = false, want true", received)
+	}
+	if got.msg != "backend unavailable" {
+		t.Errorf("recovered cause message = %q, want %q", got.msg, "backend unavailable")
+	}
+
+	if !errors.Is(received, received) {
+		t.Errorf("errors.Is(received, received) = false, want true")
+	}
+}
+
+func TestUnwrapLocalCauseTakesPrecedence(t *testing.T) {
+	cause := &myAppError{msg: "disk full"}
+	err := Wrap(codes.ResourceExhausted, cause)
+
+	if got := errors.Unwrap(err); got != cause {
+		t.Errorf("errors.Unwrap(err) = %v, want the original local cause %v", got, cause)
+	}
+}